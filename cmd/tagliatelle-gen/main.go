@@ -0,0 +1,186 @@
+// Command tagliatelle-gen rewrites struct tags in place so that they conform
+// to the same case rules enforced by the tagliatelle linter.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ldez/tagliatelle/internal/rules"
+)
+
+// rulesConfig mirrors the YAML `rules` block accepted by the linter, see
+// tagliatelle.Config. TagCheckers and CheckPromotedFields have no rewrite
+// semantics - there's no "correct" value to rewrite an unknown tag option or
+// a cross-struct promoted field to - so they aren't read here; only the
+// fields that describe a case rule are.
+type rulesConfig struct {
+	Rules        map[string]string           `yaml:"rules"`
+	UseFieldName bool                        `yaml:"useFieldName"`
+	Extras       map[string]rules.RuleConfig `yaml:"extras"`
+}
+
+func main() {
+	rulesPath := flag.String("rules", "", "path to a YAML file with a top-level `rules` block")
+	write := flag.Bool("write", false, "write result to (source) file instead of stdout")
+	tagsFlag := flag.String("tags", "", "comma-separated list of tag keys to rewrite (default: all keys in -rules)")
+	flag.Parse()
+
+	cfg, err := loadRulesConfig(*rulesPath)
+	if err != nil {
+		log.Fatalf("tagliatelle-gen: %v", err)
+	}
+
+	keys := parseTagsFlag(*tagsFlag, cfg.Rules)
+
+	for _, path := range flag.Args() {
+		if err := rewriteFile(path, cfg, keys, *write); err != nil {
+			log.Fatalf("tagliatelle-gen: %s: %v", path, err)
+		}
+	}
+}
+
+func loadRulesConfig(path string) (rulesConfig, error) {
+	var cfg rulesConfig
+
+	if path == "" {
+		return cfg, fmt.Errorf("missing -rules")
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func parseTagsFlag(tagsFlag string, allRules map[string]string) map[string]bool {
+	keys := make(map[string]bool)
+
+	if tagsFlag == "" {
+		for key := range allRules {
+			keys[key] = true
+		}
+
+		return keys
+	}
+
+	for _, key := range strings.Split(tagsFlag, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			keys[key] = true
+		}
+	}
+
+	return keys
+}
+
+func rewriteFile(path string, cfg rulesConfig, keys map[string]bool, write bool) error {
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	changed := false
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		structType, ok := n.(*ast.StructType)
+		if !ok {
+			return true
+		}
+
+		for _, field := range structType.Fields.List {
+			if rewriteField(field, cfg, keys) {
+				changed = true
+			}
+		}
+
+		return true
+	})
+
+	if !changed {
+		return nil
+	}
+
+	var buf strings.Builder
+	if err := format.Node(&buf, fset, file); err != nil {
+		return err
+	}
+
+	formatted, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return err
+	}
+
+	if !write {
+		_, err = os.Stdout.Write(formatted)
+		return err
+	}
+
+	return os.WriteFile(path, formatted, 0o644)
+}
+
+// rewriteField rewrites field's tag in place for every configured key in
+// keys, exactly the way the linter would flag it: same UseFieldName and
+// per-key Extras (Case/Ignores/Initialisms), via rules.CheckCase. Tag options
+// (e.g. ",omitempty") are left untouched. It reports whether the tag literal
+// was changed.
+func rewriteField(field *ast.Field, cfg rulesConfig, keys map[string]bool) bool {
+	if field.Tag == nil {
+		return false
+	}
+
+	fieldName, err := rules.GetFieldName(field)
+	if err != nil {
+		return false
+	}
+
+	changed := false
+
+	for key, convName := range cfg.Rules {
+		if convName == "" || !keys[key] {
+			continue
+		}
+
+		value, ok := rules.LookupTagValue(field.Tag, key)
+		if !ok {
+			continue
+		}
+
+		want, _, bad, err := rules.CheckCase(cfg.UseFieldName, cfg.Extras[key], fieldName, convName, value)
+		if err != nil || !bad {
+			continue
+		}
+
+		field.Tag.Value = replaceTagValue(field.Tag.Value, key, value, want)
+		changed = true
+	}
+
+	return changed
+}
+
+// replaceTagValue replaces the name portion of key's value inside raw (the
+// literal tag including its surrounding backticks) with want, preserving
+// every other key and every tag option.
+func replaceTagValue(raw, key, value, want string) string {
+	needle := key + `:"` + value
+	replacement := key + `:"` + want
+
+	return strings.Replace(raw, needle, replacement, 1)
+}
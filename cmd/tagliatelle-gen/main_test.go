@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ldez/tagliatelle/internal/rules"
+)
+
+func TestRewriteFile_Golden(t *testing.T) {
+	in, err := os.ReadFile("testdata/in.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden, err := os.ReadFile("testdata/in.go.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmp := filepath.Join(t.TempDir(), "in.go")
+	if err := os.WriteFile(tmp, in, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := rulesConfig{
+		Rules: map[string]string{
+			"json": "camel",
+			"yaml": "snake",
+		},
+	}
+	keys := parseTagsFlag("", cfg.Rules)
+
+	if err := rewriteFile(tmp, cfg, keys, true); err != nil {
+		t.Fatalf("rewriteFile: %v", err)
+	}
+
+	got, err := os.ReadFile(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(golden) {
+		t.Errorf("rewriteFile output mismatch:\ngot:\n%s\nwant:\n%s", got, golden)
+	}
+}
+
+func TestRewriteField_PreservesTagOptions(t *testing.T) {
+	cfg := rulesConfig{
+		Rules: map[string]string{"json": "camel"},
+		Extras: map[string]rules.RuleConfig{
+			"json": {Initialisms: []string{"ID"}},
+		},
+	}
+	keys := map[string]bool{"json": true}
+
+	tmp := filepath.Join(t.TempDir(), "multi.go")
+	src := "package testdata\n\ntype T struct {\n\tUserID string `json:\"user_id,omitempty\" xml:\"user_id\"`\n}\n"
+	if err := os.WriteFile(tmp, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := rewriteFile(tmp, cfg, keys, true); err != nil {
+		t.Fatalf("rewriteFile: %v", err)
+	}
+
+	got, err := os.ReadFile(tmp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "package testdata\n\ntype T struct {\n\tUserID string `json:\"userID,omitempty\" xml:\"user_id\"`\n}\n"
+	if string(got) != want {
+		t.Errorf("rewriteField output mismatch:\ngot:\n%s\nwant:\n%s", got, want)
+	}
+}
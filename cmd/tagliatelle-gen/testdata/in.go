@@ -0,0 +1,15 @@
+package testdata
+
+type Address struct {
+	City string `json:"City,omitempty" yaml:"City"`
+}
+
+type Resource struct {
+	Address
+
+	DisplayName string `json:"display_name,omitempty"`
+
+	Note string `json:"Note"`
+
+	Password string `json:"-"`
+}
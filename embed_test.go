@@ -0,0 +1,25 @@
+package tagliatelle_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ldez/tagliatelle"
+)
+
+// TestAnalyzer_Embedding covers embedded-field resolution via go/types: a
+// promoted field reached through a local untagged embed, and a tagged embed
+// of a struct loaded from another package.
+func TestAnalyzer_Embedding(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := tagliatelle.New(tagliatelle.Config{
+		Rules: map[string]string{
+			"json": "camel",
+		},
+		CheckPromotedFields: true,
+	})
+
+	analysistest.Run(t, testdata, analyzer, "d")
+}
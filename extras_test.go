@@ -0,0 +1,28 @@
+package tagliatelle_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ldez/tagliatelle"
+)
+
+func TestAnalyzer_Extras(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := tagliatelle.New(tagliatelle.Config{
+		Rules: map[string]string{
+			"json": "camel",
+		},
+		Extras: map[string]tagliatelle.RuleConfig{
+			"json": {
+				Case:        "snake",
+				Initialisms: []string{"ID", "URL"},
+				Ignores:     []string{"LegacyField"},
+			},
+		},
+	})
+
+	analysistest.Run(t, testdata, analyzer, "b")
+}
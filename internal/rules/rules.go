@@ -0,0 +1,277 @@
+// Package rules holds the struct-tag parsing and case-conversion logic
+// shared by the tagliatelle analyzer and the tagliatelle-gen generator.
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/ettle/strcase"
+)
+
+// GetFieldName returns the Go identifier tagliatelle should compare a tag
+// value against: the field name, or, for embedded fields, its type name.
+func GetFieldName(field *ast.Field) (string, error) {
+	var name string
+	for _, n := range field.Names {
+		if n.Name != "" {
+			name = n.Name
+		}
+	}
+
+	if name != "" {
+		return name, nil
+	}
+
+	return GetTypeName(field.Type)
+}
+
+// GetTypeName extracts the identifier of a (possibly pointer or
+// package-qualified) type expression.
+func GetTypeName(exp ast.Expr) (string, error) {
+	switch typ := exp.(type) {
+	case *ast.Ident:
+		return typ.Name, nil
+	case *ast.StarExpr:
+		return GetTypeName(typ.X)
+	case *ast.SelectorExpr:
+		return GetTypeName(typ.Sel)
+	default:
+		bytes, _ := json.Marshal(exp)
+		return "", fmt.Errorf("unexpected eror: type %T: %s", typ, string(bytes))
+	}
+}
+
+// RuleConfig is a per-tag-key override of the base case rule, shared by the
+// analyzer's Config.Extras and the generator's YAML rules file.
+type RuleConfig struct {
+	// Case overrides the converter used for this tag key.
+	Case string `yaml:"case"`
+
+	// Ignores lists field names and tag values that are exempt from the
+	// case check for this tag key.
+	Ignores []string `yaml:"ignores"`
+
+	// Initialisms lists tokens (e.g. "ID", "URL", "HTTP") that must be
+	// preserved, case-folded to match Case, after the case conversion.
+	Initialisms []string `yaml:"initialisms"`
+}
+
+// IsIgnored reports whether fieldName or value is listed in ignores.
+func IsIgnored(ignores []string, fieldName, value string) bool {
+	for _, ignore := range ignores {
+		if ignore == fieldName || ignore == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CheckCase applies the effective case rule (including any RuleConfig
+// override) for convName to value, returning the expected value, the
+// effective converter name actually applied (convName, or extra.Case if it
+// overrides it), and whether value doesn't match it. useFieldName mirrors
+// Config.UseFieldName: when true, the expected value is derived from
+// fieldName instead of value itself.
+func CheckCase(useFieldName bool, extra RuleConfig, fieldName, convName, value string) (want, effectiveConvName string, bad bool, err error) {
+	if value == "-" {
+		// skip when skipped :)
+		return "", convName, false, nil
+	}
+
+	if value == "" {
+		// skip empty value, it can change in the future
+		return "", convName, false, nil
+	}
+
+	if IsIgnored(extra.Ignores, fieldName, value) {
+		return "", convName, false, nil
+	}
+
+	if extra.Case != "" {
+		convName = extra.Case
+	}
+
+	converter, err := GetConverter(convName, extra.Initialisms)
+	if err != nil {
+		return "", convName, false, err
+	}
+
+	expected := value
+	if useFieldName {
+		expected = fieldName
+	}
+
+	want = converter(expected)
+
+	return want, convName, value != want, nil
+}
+
+// LookupTagValue returns the name portion (everything before the first
+// comma) of key's value in tag, e.g. for `json:"name,omitempty"` and key
+// "json" it returns "name".
+func LookupTagValue(tag *ast.BasicLit, key string) (string, bool) {
+	value, ok := LookupTagRaw(tag, key)
+	if !ok {
+		return value, ok
+	}
+
+	values := strings.Split(value, ",")
+
+	if len(values) < 1 {
+		return "", true
+	}
+
+	return values[0], true
+}
+
+// LookupTagRaw returns the full, unsplit value of key in tag, e.g. for
+// `json:"name,omitempty"` and key "json" it returns "name,omitempty".
+func LookupTagRaw(tag *ast.BasicLit, key string) (string, bool) {
+	raw := strings.Trim(tag.Value, "`")
+
+	return reflect.StructTag(raw).Lookup(key)
+}
+
+// GetConverter returns the case converter for c, with initialisms (e.g.
+// "ID", "URL") preserved after conversion, folded to match c's convention:
+// upper-cased for camel/pascal-style rules, lower-cased for snake/kebab/lower,
+// where an all-caps word would look out of place.
+func GetConverter(c string, initialisms []string) (func(s string) string, error) {
+	base, err := GetBaseConverter(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return withInitialisms(c, base, initialisms), nil
+}
+
+// GetBaseConverter returns the case converter for c, ignoring initialisms.
+func GetBaseConverter(c string) (func(s string) string, error) {
+	switch c {
+	case "camel":
+		return strcase.ToCamel, nil
+	case "pascal":
+		return strcase.ToPascal, nil
+	case "kebab":
+		return strcase.ToKebab, nil
+	case "snake":
+		return strcase.ToSnake, nil
+	case "goCamel":
+		return strcase.ToGoCamel, nil
+	case "goPascal":
+		return strcase.ToGoPascal, nil
+	case "goKebab":
+		return strcase.ToGoKebab, nil
+	case "goSnake":
+		return strcase.ToGoSnake, nil
+	case "upper":
+		return strings.ToUpper, nil
+	case "lower":
+		return strings.ToLower, nil
+	default:
+		return nil, fmt.Errorf("unsupported case: %s", c)
+	}
+}
+
+// withInitialisms wraps converter so that, after the case conversion, any
+// word whose upper-cased form matches one of initialisms (e.g. "ID", "URL")
+// is re-written to the form given in initialisms, folded to suit c.
+func withInitialisms(c string, converter func(s string) string, initialisms []string) func(s string) string {
+	if len(initialisms) == 0 {
+		return converter
+	}
+
+	set := make(map[string]string, len(initialisms))
+	for _, initialism := range initialisms {
+		set[strings.ToUpper(initialism)] = foldInitialism(c, initialism)
+	}
+
+	return func(s string) string {
+		return applyInitialisms(converter(s), set)
+	}
+}
+
+// foldInitialism adjusts the case of an initialism to match the convention
+// of c. snake/kebab/lower read as all-lowercase words (e.g. "user_id"), so
+// an initialism would look out of place shouted in the middle of one; every
+// other rule, including the "Go" case variants (which already special-case
+// initialisms the same way), keeps initialisms upper-cased (e.g. "userID").
+func foldInitialism(c, initialism string) string {
+	switch c {
+	case "snake", "kebab", "lower":
+		return strings.ToLower(initialism)
+	default:
+		return strings.ToUpper(initialism)
+	}
+}
+
+// wordToken is a word of a cased identifier, together with the delimiter
+// ("_", "-", or "") that preceded it.
+type wordToken struct {
+	sep  string
+	text string
+}
+
+// applyInitialisms splits s on its word boundaries and replaces any token
+// found in initialisms, keyed by its upper-cased form, with the mapped form.
+func applyInitialisms(s string, initialisms map[string]string) string {
+	tokens := splitWords(s)
+
+	for i, tok := range tokens {
+		if want, ok := initialisms[strings.ToUpper(tok.text)]; ok {
+			tokens[i].text = want
+		}
+	}
+
+	var b strings.Builder
+	for _, tok := range tokens {
+		b.WriteString(tok.sep)
+		b.WriteString(tok.text)
+	}
+
+	return b.String()
+}
+
+// splitWords splits a camelCase, PascalCase, snake_case or kebab-case string
+// into its constituent words.
+func splitWords(s string) []wordToken {
+	var tokens []wordToken
+
+	var cur []rune
+	sep := ""
+
+	runes := []rune(s)
+	flush := func() {
+		if len(cur) > 0 {
+			tokens = append(tokens, wordToken{sep: sep, text: string(cur)})
+			cur = nil
+			sep = ""
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			flush()
+			sep = string(r)
+		case i > 0 && unicode.IsUpper(r) && unicode.IsLower(runes[i-1]):
+			flush()
+			cur = append(cur, r)
+		case i > 0 && unicode.IsUpper(r) && len(cur) > 0 && unicode.IsUpper(cur[len(cur)-1]) &&
+			i+1 < len(runes) && unicode.IsLower(runes[i+1]):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+	}
+	flush()
+
+	return tokens
+}
@@ -0,0 +1,22 @@
+package tagliatelle_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ldez/tagliatelle"
+)
+
+func TestAnalyzer_TagCheckers(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := tagliatelle.New(tagliatelle.Config{
+		Rules: map[string]string{
+			"json": "camel",
+			"env":  "upper",
+		},
+	})
+
+	analysistest.Run(t, testdata, analyzer, "c")
+}
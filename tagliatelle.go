@@ -6,12 +6,14 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/token"
+	"go/types"
 	"reflect"
 	"strings"
 
 	"github.com/ldez/tagliatelle/filedtype"
+	"github.com/ldez/tagliatelle/internal/rules"
 
-	"github.com/ettle/strcase"
 	"golang.org/x/tools/go/analysis"
 	"golang.org/x/tools/go/analysis/passes/inspect"
 	"golang.org/x/tools/go/ast/inspector"
@@ -21,8 +23,33 @@ import (
 type Config struct {
 	Rules        map[string]string
 	UseFieldName bool
+
+	// Extras holds per-rule overrides, keyed by tag key (e.g. "json", "yaml").
+	Extras map[string]RuleConfig
+
+	// TagCheckers overrides or adds extended per-option validation for a tag
+	// key. Keys not present here fall back to the built-in checkers, see
+	// defaultTagCheckers. A checker runs only for keys that also have a
+	// non-empty case rule in Rules.
+	TagCheckers map[string]TagChecker
+
+	// CheckPromotedFields, when true, also lints the tags of the exported
+	// fields of anonymous (embedded) struct fields that carry no tag of
+	// their own, since Go's encoding packages promote those fields onto the
+	// outer struct.
+	CheckPromotedFields bool
 }
 
+// TagChecker validates the options of a struct tag value (everything beyond
+// the name), e.g. ",omitempty" in `json:"name,omitempty"`. It is dispatched
+// per tag key, in addition to the name case check.
+type TagChecker interface {
+	Check(pass *analysis.Pass, field *ast.Field, key, rawValue string) []analysis.Diagnostic
+}
+
+// RuleConfig is a per-tag-key override of the base case rule.
+type RuleConfig = rules.RuleConfig
+
 // New creates an analyzer.
 func New(config Config) *analysis.Analyzer {
 	return &analysis.Analyzer{
@@ -72,11 +99,18 @@ func analyze(pass *analysis.Pass, config Config, n *ast.StructType, field *ast.F
 	}
 
 	if field.Tag == nil {
-		// skip when no struct tag
+		// An embedded field without its own tag doesn't get linted itself,
+		// but its exported fields are promoted onto the outer struct by
+		// encoding/json, encoding/yaml, etc., so lint those instead.
+		if config.CheckPromotedFields && len(field.Names) == 0 {
+			if t := pass.TypesInfo.TypeOf(field.Type); t != nil {
+				checkPromotedFields(pass, config, t)
+			}
+		}
 		return
 	}
 
-	fieldName, err := getFieldName(field)
+	fieldName, err := resolveFieldName(pass, field)
 	if err != nil {
 		pass.Reportf(n.Pos(), "unable to get field name: %v", err)
 		return
@@ -93,70 +127,211 @@ func analyze(pass *analysis.Pass, config Config, n *ast.StructType, field *ast.F
 		if convName == "" {
 			continue
 		}
-		baseLint(pass, config, n, field.Tag, fieldName, key, convName)
+		baseLint(pass, config, n, field.Tag, fieldName, key, convName, config.Extras[key])
 		//gin.Lint(pass, field.Tag, fieldTypes, key, convName)
+		checkTagOptions(pass, config, field, key)
 	}
 }
 
-func baseLint(pass *analysis.Pass, config Config, n *ast.StructType, tag *ast.BasicLit, fieldName, key, convName string) {
-	value, ok := lookupTagValue(tag, key)
+// checkTagOptions dispatches the extended per-option validation (unknown
+// options, misspelled booleans, ...) for key, using the checker registered
+// in config.TagCheckers or, failing that, defaultTagCheckers.
+func checkTagOptions(pass *analysis.Pass, config Config, field *ast.Field, key string) {
+	checker, ok := config.TagCheckers[key]
+	if !ok {
+		checker, ok = defaultTagCheckers[key]
+	}
 	if !ok {
-		// skip when no struct tag for the key
 		return
 	}
 
-	if value == "-" {
-		// skip when skipped :)
+	rawValue, ok := rules.LookupTagRaw(field.Tag, key)
+	if !ok || rawValue == "-" {
 		return
 	}
 
-	if value == "" {
-		// skip empty value, it can change in the future
+	for _, diag := range checker.Check(pass, field, key, rawValue) {
+		pass.Report(diag)
+	}
+}
+
+func baseLint(pass *analysis.Pass, config Config, n *ast.StructType, tag *ast.BasicLit, fieldName, key, convName string, extra RuleConfig) {
+	value, ok := rules.LookupTagValue(tag, key)
+	if !ok {
+		// skip when no struct tag for the key
 		return
 	}
 
-	converter, err := getConverter(convName)
+	want, effectiveConvName, bad, err := rules.CheckCase(config.UseFieldName, extra, fieldName, convName, value)
 	if err != nil {
-		pass.Reportf(n.Pos(), "%s(%s): %v", key, convName, err)
+		pass.Reportf(n.Pos(), "%s(%s): %v", key, effectiveConvName, err)
 		return
 	}
 
-	expected := value
-	if config.UseFieldName {
-		expected = fieldName
+	if !bad {
+		return
 	}
 
-	if value != converter(expected) {
-		pass.Reportf(tag.Pos(), "%s(%s): got '%s' want '%s'", key, convName, value, converter(expected))
+	pass.Report(analysis.Diagnostic{
+		Pos:     tag.Pos(),
+		Message: fmt.Sprintf("%s(%s): got '%s' want '%s'", key, effectiveConvName, value, want),
+		SuggestedFixes: []analysis.SuggestedFix{
+			{
+				Message: fmt.Sprintf("rewrite tag value to '%s'", want),
+				TextEdits: []analysis.TextEdit{
+					newTagValueEdit(tag, key, value, want),
+				},
+			},
+		},
+	})
+}
+
+// newTagValueEdit builds the analysis.TextEdit that replaces the value of key
+// inside the raw tag literal with want, leaving every other key and every tag
+// option (e.g. ",omitempty") untouched.
+func newTagValueEdit(tag *ast.BasicLit, key, value, want string) analysis.TextEdit {
+	start, end := tagValueOffset(tag.Value, key, value)
+
+	return analysis.TextEdit{
+		Pos:     tag.Pos() + token.Pos(start),
+		End:     tag.Pos() + token.Pos(end),
+		NewText: []byte(want),
 	}
 }
 
-func getFieldName(field *ast.Field) (string, error) {
-	var name string
-	for _, n := range field.Names {
-		if n.Name != "" {
-			name = n.Name
-		}
+// tagValueOffset returns the byte offsets, relative to the start of raw (the
+// literal tag including its surrounding backticks), of the name portion of
+// key's value. For example, with raw holding the tag json:"userId,omitempty"
+// and key set to json, it returns the offsets of userId.
+func tagValueOffset(raw, key, value string) (int, int) {
+	needle := key + `:"`
+
+	idx := strings.Index(raw, needle)
+	if idx < 0 {
+		return 0, 0
+	}
+
+	start := idx + len(needle)
+
+	return start, start + len(value)
+}
+
+// resolveFieldName is like rules.GetFieldName, but for an embedded field it
+// consults pass.TypesInfo instead of the bare AST, so that type aliases
+// (type UserID = string) and cross-package or generic embeds still resolve
+// to the identifier written at the embed site.
+func resolveFieldName(pass *analysis.Pass, field *ast.Field) (string, error) {
+	if len(field.Names) > 0 {
+		return rules.GetFieldName(field)
 	}
 
-	if name != "" {
-		return name, nil
+	if ident := typeIdent(field.Type); ident != nil {
+		if obj, ok := pass.TypesInfo.Uses[ident]; ok {
+			return obj.Name(), nil
+		}
 	}
 
-	return getTypeName(field.Type)
+	return rules.GetFieldName(field)
 }
 
-func getTypeName(exp ast.Expr) (string, error) {
-	switch typ := exp.(type) {
+// typeIdent returns the identifier naming exp's type, unwrapping pointers,
+// package qualifiers and generic type arguments.
+func typeIdent(exp ast.Expr) *ast.Ident {
+	switch e := exp.(type) {
 	case *ast.Ident:
-		return typ.Name, nil
+		return e
 	case *ast.StarExpr:
-		return getTypeName(typ.X)
+		return typeIdent(e.X)
 	case *ast.SelectorExpr:
-		return getTypeName(typ.Sel)
+		return typeIdent(e.Sel)
+	case *ast.IndexExpr:
+		return typeIdent(e.X)
+	case *ast.IndexListExpr:
+		return typeIdent(e.X)
 	default:
-		bytes, _ := json.Marshal(exp)
-		return "", fmt.Errorf("unexpected eror: type %T: %s", typ, string(bytes))
+		return nil
+	}
+}
+
+// checkPromotedFields recursively lints the exported, tag-less-embed-chain
+// fields of t (the type of an anonymous field with no tag of its own)
+// against config.Rules, mirroring the promotion rules used by encoding/json
+// and friends.
+func checkPromotedFields(pass *analysis.Pass, config Config, t types.Type) {
+	checkPromotedFieldsSeen(pass, config, t, make(map[*types.Struct]bool))
+}
+
+// checkPromotedFieldsSeen is checkPromotedFields with a set of already-walked
+// struct types, so that a self-referential embed (e.g. `type Node struct {
+// *Node; ... }`, a legal recursive structure) doesn't recurse forever.
+func checkPromotedFieldsSeen(pass *analysis.Pass, config Config, t types.Type, seen map[*types.Struct]bool) {
+	structType, ok := underlyingStruct(t)
+	if !ok || seen[structType] {
+		return
+	}
+	seen[structType] = true
+
+	for i := 0; i < structType.NumFields(); i++ {
+		obj := structType.Field(i)
+		if !obj.Exported() {
+			continue
+		}
+
+		tagValue := structType.Tag(i)
+
+		if obj.Embedded() && tagValue == "" {
+			checkPromotedFieldsSeen(pass, config, obj.Type(), seen)
+			continue
+		}
+
+		checkPromotedField(pass, config, obj, tagValue)
+	}
+}
+
+// checkPromotedField applies config.Rules to a single promoted field. There
+// is no local *ast.BasicLit to attach a SuggestedFix to - the tag lives on a
+// struct declared elsewhere - so diagnostics are plain.
+func checkPromotedField(pass *analysis.Pass, config Config, obj *types.Var, rawTag string) {
+	tag := reflect.StructTag(rawTag)
+
+	for key, convName := range config.Rules {
+		if convName == "" {
+			continue
+		}
+
+		raw, ok := tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		value := strings.SplitN(raw, ",", 2)[0]
+
+		want, effectiveConvName, bad, err := rules.CheckCase(config.UseFieldName, config.Extras[key], obj.Name(), convName, value)
+		if err != nil {
+			pass.Reportf(obj.Pos(), "%s(%s): %v", key, effectiveConvName, err)
+			continue
+		}
+
+		if bad {
+			pass.Reportf(obj.Pos(), "%s(%s): got '%s' want '%s' (promoted field %s)", key, effectiveConvName, value, want, obj.Name())
+		}
+	}
+}
+
+// underlyingStruct follows pointers and named types to the underlying
+// struct type of t, if any.
+func underlyingStruct(t types.Type) (*types.Struct, bool) {
+	for {
+		switch typ := t.(type) {
+		case *types.Pointer:
+			t = typ.Elem()
+		case *types.Named:
+			t = typ.Underlying()
+		case *types.Struct:
+			return typ, true
+		default:
+			return nil, false
+		}
 	}
 }
 
@@ -179,46 +354,68 @@ func getFieldTypes(exp ast.Expr) []filedtype.FiledType {
 	}
 }
 
-func lookupTagValue(tag *ast.BasicLit, key string) (string, bool) {
-	raw := strings.Trim(tag.Value, "`")
+// defaultTagCheckers are the built-in TagChecker implementations, registered
+// for the tag keys tagliatelle knows how to parse options for.
+var defaultTagCheckers = map[string]TagChecker{
+	"json":         newOptionChecker(set("omitempty", "string")),
+	"yaml":         newOptionChecker(set("omitempty", "flow", "inline")),
+	"xml":          newOptionChecker(set("omitempty", "attr", "chardata", "cdata", "comment", "innerxml", "any")),
+	"mapstructure": newOptionChecker(set("omitempty", "squash", "remain")),
+	"form":         newOptionChecker(set("omitempty")),
+	"header":       newOptionChecker(nil),
+	"url":          newOptionChecker(set("omitempty")),
+	"toml":         newOptionChecker(set("omitempty")),
+	"env": &optionChecker{
+		boolOptions:  set("required", "expand", "unset", "file"),
+		valueOptions: set("default", "separator", "envSeparator", "envExpand"),
+	},
+}
 
-	value, ok := reflect.StructTag(raw).Lookup(key)
-	if !ok {
-		return value, ok
-	}
-
-	values := strings.Split(value, ",")
-
-	if len(values) < 1 {
-		return "", true
-	}
-
-	return values[0], true
-}
-
-func getConverter(c string) (func(s string) string, error) {
-	switch c {
-	case "camel":
-		return strcase.ToCamel, nil
-	case "pascal":
-		return strcase.ToPascal, nil
-	case "kebab":
-		return strcase.ToKebab, nil
-	case "snake":
-		return strcase.ToSnake, nil
-	case "goCamel":
-		return strcase.ToGoCamel, nil
-	case "goPascal":
-		return strcase.ToGoPascal, nil
-	case "goKebab":
-		return strcase.ToGoKebab, nil
-	case "goSnake":
-		return strcase.ToGoSnake, nil
-	case "upper":
-		return strings.ToUpper, nil
-	case "lower":
-		return strings.ToLower, nil
-	default:
-		return nil, fmt.Errorf("unsupported case: %s", c)
+func set(options ...string) map[string]bool {
+	m := make(map[string]bool, len(options))
+	for _, o := range options {
+		m[o] = true
+	}
+
+	return m
+}
+
+// optionChecker is a generic TagChecker that reports any tag option that is
+// neither a known bare boolean option (e.g. "omitempty") nor a known
+// "name=value" option (e.g. "default=...").
+type optionChecker struct {
+	boolOptions  map[string]bool
+	valueOptions map[string]bool
+}
+
+func newOptionChecker(boolOptions map[string]bool) *optionChecker {
+	return &optionChecker{boolOptions: boolOptions}
+}
+
+func (c *optionChecker) Check(_ *analysis.Pass, field *ast.Field, key, rawValue string) []analysis.Diagnostic {
+	parts := strings.Split(rawValue, ",")
+
+	var diagnostics []analysis.Diagnostic
+
+	for _, opt := range parts[1:] {
+		if opt == "" {
+			continue
+		}
+
+		name := opt
+		if idx := strings.Index(opt, "="); idx >= 0 {
+			name = opt[:idx]
+		}
+
+		if c.boolOptions[name] || c.valueOptions[name] {
+			continue
+		}
+
+		diagnostics = append(diagnostics, analysis.Diagnostic{
+			Pos:     field.Tag.Pos(),
+			Message: fmt.Sprintf("%s: unknown tag option %q", key, opt),
+		})
 	}
+
+	return diagnostics
 }
@@ -0,0 +1,25 @@
+package tagliatelle_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/ldez/tagliatelle"
+)
+
+func TestAnalyzer_SuggestedFixes(t *testing.T) {
+	testdata := analysistest.TestData()
+
+	analyzer := tagliatelle.New(tagliatelle.Config{
+		Rules: map[string]string{
+			"json": "camel",
+			"yaml": "snake",
+		},
+		Extras: map[string]tagliatelle.RuleConfig{
+			"json": {Initialisms: []string{"ID"}},
+		},
+	})
+
+	analysistest.RunWithSuggestedFixes(t, testdata, analyzer, "a")
+}
@@ -0,0 +1,16 @@
+package a
+
+type User struct {
+	// UserID is already camelCase-compliant under json, but its yaml key
+	// needs rewriting.
+	UserID string `json:"userID" yaml:"UserID,omitempty"` // want `yaml\(snake\): got 'UserID' want 'user_id'`
+
+	// DisplayName is snake_case under json, which camel rejects.
+	DisplayName string `json:"display_name,omitempty"` // want `json\(camel\): got 'display_name' want 'displayName'`
+
+	// Password is explicitly skipped for json.
+	Password string `json:"-"`
+
+	// Inline is promoted, its empty name is exempt from the case check.
+	Inline *User `yaml:",inline"`
+}
@@ -0,0 +1,16 @@
+package b
+
+type Resource struct {
+	// UserID: the base json rule is camel, but Extras overrides it to snake
+	// and folds the "ID"/"URL" initialisms to lowercase to match.
+	UserID string `json:"user_id"`
+
+	// ThingURL exercises the same initialism folding.
+	ThingURL string `json:"thing_url"`
+
+	// Legacy is deliberately wrong but explicitly ignored.
+	Legacy string `json:"LegacyField"`
+
+	// Bad is wrong and not ignored.
+	Bad string `json:"Bad_Name"` // want `json\(snake\): got 'Bad_Name' want 'bad_name'`
+}
@@ -0,0 +1,11 @@
+package c
+
+type Config struct {
+	Host string `env:"HOST,required"`
+
+	Port string `env:"PORT,requried"` // want `env: unknown tag option "requried"`
+
+	Name string `json:"name,omitempty"`
+
+	Bad string `json:"bad,omitEmpty"` // want `json: unknown tag option "omitEmpty"`
+}
@@ -0,0 +1,8 @@
+// Package common provides a struct embedded cross-package by testdata/src/d,
+// to exercise tagliatelle's go/types-based resolution of embedded field
+// names.
+package common
+
+type Info struct {
+	FullName string
+}
@@ -0,0 +1,21 @@
+package d
+
+import "d/common"
+
+type Inner struct {
+	Age string `json:"Age"` // want `json\(camel\): got 'Age' want 'age'` `json\(camel\): got 'Age' want 'age' \(promoted field Age\)`
+}
+
+// Outer embeds Inner without a tag of its own, so Inner.Age is promoted onto
+// Outer and must be linted even though Outer never mentions it directly.
+type Outer struct {
+	Inner
+}
+
+// Wrapper embeds a struct from another package, loaded alongside d via
+// go/packages, tagged so it's linted in its own right. Its field name
+// ("Info") is resolved through pass.TypesInfo from the cross-package
+// selector.
+type Wrapper struct {
+	common.Info `json:"Info"` // want `json\(camel\): got 'Info' want 'info'`
+}